@@ -0,0 +1,88 @@
+package kube
+
+import (
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+)
+
+// ConfigMapName is the ConfigMap cluster operators use to override scanner
+// defaults, such as the kube-bench image, per environment without
+// recompiling.
+const ConfigMapName = "starboard-config"
+
+// ScannerOpts groups options to configure behaviour of scanners that launch
+// Jobs into the cluster, such as the kube-bench Scanner.
+type ScannerOpts struct {
+	// ScanJobTimeout is the maximum time to wait for a scan Job to complete
+	// before it's considered failed.
+	ScanJobTimeout time.Duration
+
+	// Concurrency bounds how many scan Jobs a Scanner may have in flight at
+	// once when fanning a scan out across multiple Nodes. Defaults to 1
+	// (fully sequential) when left unset.
+	Concurrency int
+
+	// BenchmarkProfile overrides auto-detection of the kube-bench benchmark
+	// target for a Node, for distributions the Scanner doesn't recognise on
+	// its own. Left empty, the Scanner picks a profile from the Node's
+	// labels and provider ID.
+	BenchmarkProfile string
+
+	// KubeBenchImageRef overrides the default kube-bench image reference,
+	// for air-gapped installs, pinning to a specific CIS-benchmark tag, or
+	// use of a private registry. Left empty, the Scanner's built-in default
+	// is used.
+	KubeBenchImageRef string
+
+	// ImagePullPolicy overrides the default pull policy for the kube-bench
+	// image. Left empty, the Scanner's built-in default is used.
+	ImagePullPolicy core.PullPolicy
+
+	// ImagePullSecrets names the Secrets used to pull the kube-bench image
+	// from a private registry.
+	ImagePullSecrets []string
+
+	// ResolveImageDigest, when true, resolves KubeBenchImageRef to a digest
+	// once at Scanner construction time, so every scan run by that Scanner
+	// uses the exact same image even if a mutable tag such as :latest moves
+	// upstream.
+	ResolveImageDigest bool
+
+	// NodeSelector overrides the Scanner's per-profile default NodeSelector
+	// for the scan Pod.
+	NodeSelector map[string]string
+
+	// Tolerations overrides the Scanner's per-profile default Tolerations
+	// for the scan Pod.
+	Tolerations []core.Toleration
+
+	// Affinity sets the scan Pod's Affinity. There's no per-profile
+	// default, so this is only ever the caller's own setting.
+	Affinity *core.Affinity
+
+	// ServiceAccountName sets the ServiceAccountName the scan Pod runs as.
+	ServiceAccountName string
+
+	// PriorityClassName overrides the Scanner's per-profile default
+	// PriorityClassName for the scan Pod, so it doesn't get evicted under
+	// pressure.
+	PriorityClassName string
+}
+
+// ScannerOptsFromConfigMap builds a ScannerOpts from the starboard-config
+// ConfigMap. A key absent from cm.Data leaves the corresponding field at
+// its zero value, so callers can overlay it on top of their own defaults.
+func ScannerOptsFromConfigMap(cm *core.ConfigMap) ScannerOpts {
+	var opts ScannerOpts
+
+	opts.KubeBenchImageRef = cm.Data["kube-bench.imageRef"]
+	opts.ImagePullPolicy = core.PullPolicy(cm.Data["kube-bench.imagePullPolicy"])
+	if secrets := cm.Data["kube-bench.imagePullSecrets"]; secrets != "" {
+		opts.ImagePullSecrets = strings.Split(secrets, ",")
+	}
+	opts.ResolveImageDigest = cm.Data["kube-bench.resolveImageDigest"] == "true"
+
+	return opts
+}
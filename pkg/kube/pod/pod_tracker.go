@@ -0,0 +1,194 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+const (
+	labelApp     = "app"
+	labelJobName = "job-name"
+
+	appKubeBench = "kube-bench"
+
+	reasonImagePullBackOff = "ImagePullBackOff"
+	reasonErrImagePull     = "ErrImagePull"
+)
+
+// podResult is delivered to a WaitForCompletion caller once the Pod it's
+// watching has terminated, or failed fast because it couldn't make
+// progress.
+type podResult struct {
+	pod *core.Pod
+	err error
+}
+
+// PodTracker watches Pods and the Events reported against them through a
+// shared informer factory scoped to kube.NamespaceStarboard, so callers can
+// wait for a Job's Pod to finish without polling the API server. It
+// replaces sequentially blocking on a Job runner and then fetching logs,
+// which racks up wall time on slow schedulers or image pulls.
+type PodTracker struct {
+	factory       informers.SharedInformerFactory
+	podInformer   cache.SharedIndexInformer
+	eventInformer cache.SharedIndexInformer
+
+	mu      sync.Mutex
+	waiters map[string]chan podResult
+}
+
+// NewPodTracker starts a shared informer factory scoped to
+// kube.NamespaceStarboard, watching Pods and Events, and blocks until both
+// informers have synced, ctx is done, or the Scan/ScanAll driving it decides
+// to give up early - whichever comes first. stopCh should be closed once the
+// tracker, and the scan it backs, are done.
+func NewPodTracker(ctx context.Context, clientset kubernetes.Interface, stopCh <-chan struct{}) (*PodTracker, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(kube.NamespaceStarboard))
+
+	t := &PodTracker{
+		factory:       factory,
+		podInformer:   factory.Core().V1().Pods().Informer(),
+		eventInformer: factory.Core().V1().Events().Informer(),
+		waiters:       make(map[string]chan podResult),
+	}
+
+	t.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    t.onPod,
+		UpdateFunc: func(_, obj interface{}) { t.onPod(obj) },
+	})
+	t.eventInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: t.onEvent,
+	})
+
+	factory.Start(stopCh)
+
+	synced := make(chan bool, 1)
+	go func() {
+		synced <- cache.WaitForCacheSync(stopCh, t.podInformer.HasSynced, t.eventInformer.HasSynced)
+	}()
+
+	select {
+	case ok := <-synced:
+		if !ok {
+			return nil, fmt.Errorf("waiting for pod tracker cache to sync")
+		}
+		return t, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for pod tracker cache to sync: %w", ctx.Err())
+	}
+}
+
+// WaitForCompletion blocks until the Pod controlled by the Job named
+// jobName reaches a terminal phase and returns it, together with an error if
+// that phase is Failed, or returns early with an error if the Pod can't make
+// progress, e.g. ImagePullBackOff, instead of waiting out the Job's
+// ActiveDeadlineSeconds. A Pod that the informer already holds when
+// WaitForCompletion is called is checked as well.
+func (t *PodTracker) WaitForCompletion(ctx context.Context, jobName string) (*core.Pod, error) {
+	ch := make(chan podResult, 1)
+
+	t.mu.Lock()
+	t.waiters[jobName] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.waiters, jobName)
+		t.mu.Unlock()
+	}()
+
+	// The Pod may have been seen by the informer before this waiter was
+	// registered, so its cached state is checked too.
+	for _, obj := range t.podInformer.GetStore().List() {
+		t.onPod(obj)
+	}
+
+	select {
+	case result := <-ch:
+		return result.pod, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *PodTracker) onPod(obj interface{}) {
+	kubeBenchPod, ok := obj.(*core.Pod)
+	if !ok || kubeBenchPod.Labels[labelApp] != appKubeBench {
+		return
+	}
+
+	switch kubeBenchPod.Status.Phase {
+	case core.PodSucceeded:
+		t.deliver(kubeBenchPod.Labels[labelJobName], podResult{pod: kubeBenchPod})
+		return
+	case core.PodFailed:
+		t.deliver(kubeBenchPod.Labels[labelJobName], podResult{pod: kubeBenchPod,
+			err: fmt.Errorf("pod %s/%s failed", kubeBenchPod.Namespace, kubeBenchPod.Name)})
+		return
+	}
+
+	if reason, stuck := imagePullFailureReason(kubeBenchPod); stuck {
+		klog.Errorf("Pod %s/%s failed to pull its image, failing fast: %s",
+			kubeBenchPod.Namespace, kubeBenchPod.Name, reason)
+		t.deliver(kubeBenchPod.Labels[labelJobName], podResult{err: fmt.Errorf("pulling image: %s", reason)})
+	}
+}
+
+// imagePullFailureReason reports whether pod has a container stuck unable
+// to pull its image, per its ContainerStatuses' Waiting reason. This is the
+// Pod-level signal for a stuck pull; the corresponding "BackOff" Event's
+// Message ("Back-off pulling image ...") doesn't carry the reason, so it
+// can't be pattern-matched there.
+func imagePullFailureReason(pod *core.Pod) (reason string, stuck bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		switch status.State.Waiting.Reason {
+		case reasonImagePullBackOff, reasonErrImagePull:
+			return status.State.Waiting.Reason, true
+		}
+	}
+	return "", false
+}
+
+func (t *PodTracker) onEvent(obj interface{}) {
+	event, ok := obj.(*core.Event)
+	if !ok || event.InvolvedObject.Kind != "Pod" {
+		return
+	}
+
+	switch event.Reason {
+	case "Scheduled", "Pulling", "Pulled", "Started":
+		klog.V(3).Infof("Pod %s/%s: %s: %s", event.InvolvedObject.Namespace, event.InvolvedObject.Name,
+			event.Reason, event.Message)
+	case "Failed":
+		klog.Errorf("Pod %s/%s: %s: %s", event.InvolvedObject.Namespace, event.InvolvedObject.Name,
+			event.Reason, event.Message)
+	}
+}
+
+func (t *PodTracker) deliver(jobName string, result podResult) {
+	if jobName == "" {
+		return
+	}
+	t.mu.Lock()
+	ch, ok := t.waiters[jobName]
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case ch <- result:
+	default:
+	}
+}
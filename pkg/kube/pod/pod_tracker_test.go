@@ -0,0 +1,162 @@
+package pod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+func TestImagePullFailureReason(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pod        *core.Pod
+		wantReason string
+		wantStuck  bool
+	}{
+		{
+			name: "no container statuses yet",
+			pod:  &core.Pod{},
+		},
+		{
+			name: "container running",
+			pod: &core.Pod{
+				Status: core.PodStatus{
+					ContainerStatuses: []core.ContainerStatus{
+						{State: core.ContainerState{Running: &core.ContainerStateRunning{}}},
+					},
+				},
+			},
+		},
+		{
+			name: "container waiting on something other than a pull",
+			pod: &core.Pod{
+				Status: core.PodStatus{
+					ContainerStatuses: []core.ContainerStatus{
+						{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "image pull back-off",
+			pod: &core.Pod{
+				Status: core.PodStatus{
+					ContainerStatuses: []core.ContainerStatus{
+						{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+					},
+				},
+			},
+			wantReason: "ImagePullBackOff",
+			wantStuck:  true,
+		},
+		{
+			name: "image pull error",
+			pod: &core.Pod{
+				Status: core.PodStatus{
+					ContainerStatuses: []core.ContainerStatus{
+						{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ErrImagePull"}}},
+					},
+				},
+			},
+			wantReason: "ErrImagePull",
+			wantStuck:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, stuck := imagePullFailureReason(tc.pod)
+			if stuck != tc.wantStuck || reason != tc.wantReason {
+				t.Errorf("imagePullFailureReason() = (%q, %v), want (%q, %v)", reason, stuck, tc.wantReason, tc.wantStuck)
+			}
+		})
+	}
+}
+
+// TestPodTracker_WaitForCompletion drives a PodTracker against a fake
+// clientset to exercise the three ways a tracked Pod can resolve: reaching a
+// terminal phase successfully, reaching a terminal phase by failing, and
+// failing fast on a stuck image pull before ever reaching a terminal phase.
+func TestPodTracker_WaitForCompletion(t *testing.T) {
+	const jobName = "kube-bench-job"
+
+	testCases := []struct {
+		name    string
+		pod     *core.Pod
+		wantErr bool
+	}{
+		{
+			name: "pod succeeds",
+			pod: &core.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      "kube-bench-pod",
+					Namespace: kube.NamespaceStarboard,
+					Labels:    map[string]string{labelApp: appKubeBench, labelJobName: jobName},
+				},
+				Status: core.PodStatus{Phase: core.PodSucceeded},
+			},
+		},
+		{
+			name: "pod fails",
+			pod: &core.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      "kube-bench-pod",
+					Namespace: kube.NamespaceStarboard,
+					Labels:    map[string]string{labelApp: appKubeBench, labelJobName: jobName},
+				},
+				Status: core.PodStatus{Phase: core.PodFailed},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pod stuck in image pull back-off fails fast",
+			pod: &core.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      "kube-bench-pod",
+					Namespace: kube.NamespaceStarboard,
+					Labels:    map[string]string{labelApp: appKubeBench, labelJobName: jobName},
+				},
+				Status: core.PodStatus{
+					ContainerStatuses: []core.ContainerStatus{
+						{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: reasonImagePullBackOff}}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			tracker, err := NewPodTracker(context.Background(), clientset, stopCh)
+			if err != nil {
+				t.Fatalf("NewPodTracker() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if _, err := clientset.CoreV1().Pods(kube.NamespaceStarboard).Create(ctx, tc.pod, meta.CreateOptions{}); err != nil {
+				t.Fatalf("creating pod: %v", err)
+			}
+
+			gotPod, err := tracker.WaitForCompletion(ctx, jobName)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("WaitForCompletion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && gotPod.Name != tc.pod.Name {
+				t.Errorf("WaitForCompletion() pod = %q, want %q", gotPod.Name, tc.pod.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package kubebench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/client-go/kubernetes"
+)
+
+// validateImageRef checks that ref is a well-formed image reference before
+// it's ever threaded into a Job spec, so a typo'd KubeBenchImageRef fails
+// fast at Scanner construction rather than as an ImagePullBackOff.
+func validateImageRef(ref string) error {
+	if _, err := reference.ParseNormalizedNamed(ref); err != nil {
+		return fmt.Errorf("parsing image reference %q: %w", ref, err)
+	}
+	return nil
+}
+
+// resolveImageDigest resolves ref to its current digest, so a Scanner
+// pinned to a mutable tag such as aquasec/kube-bench:latest runs the exact
+// same image across every scan it performs, rather than whatever the tag
+// happens to point to at the time. Registry credentials are built from
+// imagePullSecrets (looked up in namespace) the same way the kube-bench Pod
+// itself authenticates, so resolving the digest of a private image works
+// without any extra configuration.
+func resolveImageDigest(ctx context.Context, clientset kubernetes.Interface, namespace string, imagePullSecrets []string, ref string) (string, error) {
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return "", fmt.Errorf("parsing image reference %q: %w", ref, err)
+	}
+
+	keychain, err := k8schain.New(ctx, clientset, k8schain.Options{
+		Namespace:        namespace,
+		ImagePullSecrets: imagePullSecrets,
+	})
+	if err != nil {
+		return "", fmt.Errorf("building registry credentials: %w", err)
+	}
+
+	desc, err := remote.Get(parsed, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return "", fmt.Errorf("resolving digest for %q: %w", ref, err)
+	}
+
+	return fmt.Sprintf("%s@%s", parsed.Context().Name(), desc.Digest), nil
+}
@@ -0,0 +1,28 @@
+package kubebench
+
+import "testing"
+
+func TestValidateImageRef(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "tagged ref", ref: "aquasec/kube-bench:latest"},
+		{name: "digest ref", ref: "aquasec/kube-bench@sha256:" + sha256Hex},
+		{name: "private registry ref", ref: "my-registry.example.com:5000/aquasec/kube-bench:v0.6.8"},
+		{name: "empty ref is invalid", ref: "", wantErr: true},
+		{name: "uppercase repo is invalid", ref: "Aquasec/Kube-Bench:latest", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateImageRef(tc.ref)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateImageRef(%q) error = %v, wantErr %v", tc.ref, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+const sha256Hex = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
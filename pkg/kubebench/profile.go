@@ -0,0 +1,170 @@
+package kubebench
+
+import (
+	"regexp"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+)
+
+// BenchmarkProfile identifies which kube-bench benchmark a scan should run,
+// so that only the checks (and host mounts) relevant to a Node are used.
+// Running the master benchmark against a worker node, or mounting
+// /var/lib/etcd on a managed control plane the cluster operator can't
+// access, is both wasted and noisy.
+type BenchmarkProfile string
+
+const (
+	BenchmarkMaster   BenchmarkProfile = "master"
+	BenchmarkNode     BenchmarkProfile = "node"
+	BenchmarkEtcd     BenchmarkProfile = "etcd"
+	BenchmarkPolicies BenchmarkProfile = "policies"
+	BenchmarkEKS      BenchmarkProfile = "eks"
+	BenchmarkGKE      BenchmarkProfile = "gke"
+	BenchmarkAKS      BenchmarkProfile = "aks"
+)
+
+const (
+	labelNodeRoleMaster = "node-role.kubernetes.io/master"
+	labelInstanceType   = "node.kubernetes.io/instance-type"
+)
+
+// instanceTypePatterns matches the instance-type label's naming convention
+// to the cloud that minted it, as a fallback for nodes whose ProviderID is
+// missing or doesn't use one of the well-known URI schemes below.
+var instanceTypePatterns = map[BenchmarkProfile]*regexp.Regexp{
+	BenchmarkEKS: regexp.MustCompile(`^[a-z][0-9][a-z]*\.(nano|micro|small|medium|large|[0-9]*xlarge)$`),
+	BenchmarkGKE: regexp.MustCompile(`^[a-z][0-9]-[a-z]+-[0-9]+$`),
+	BenchmarkAKS: regexp.MustCompile(`^Standard_`),
+}
+
+// resolveBenchmarkProfile infers the BenchmarkProfile to run against node
+// from its labels and provider ID, unless s.opts.BenchmarkProfile overrides
+// auto-detection for distributions kube-bench doesn't recognise. node is
+// nil when the kube-bench Pod hasn't been scheduled yet, in which case
+// BenchmarkNode is assumed.
+//
+// The master-role label is checked before the provider ID, so a
+// self-hosted control-plane node running on cloud infrastructure (a
+// kubeadm master on an EC2/GCE/Azure VM still gets a cloud ProviderID) is
+// still scanned as BenchmarkMaster rather than being misclassified as a
+// managed control plane.
+func (s *Scanner) resolveBenchmarkProfile(node *core.Node) BenchmarkProfile {
+	if s.opts.BenchmarkProfile != "" {
+		return BenchmarkProfile(s.opts.BenchmarkProfile)
+	}
+	if node == nil {
+		return BenchmarkNode
+	}
+
+	if _, ok := node.Labels[labelNodeRoleMaster]; ok {
+		return BenchmarkMaster
+	}
+
+	switch {
+	case strings.HasPrefix(node.Spec.ProviderID, "aws://"):
+		return BenchmarkEKS
+	case strings.HasPrefix(node.Spec.ProviderID, "gce://"):
+		return BenchmarkGKE
+	case strings.HasPrefix(node.Spec.ProviderID, "azure://"):
+		return BenchmarkAKS
+	}
+
+	if profile, ok := profileFromInstanceType(node.Labels[labelInstanceType]); ok {
+		return profile
+	}
+
+	return BenchmarkNode
+}
+
+// profileFromInstanceType infers a managed-cloud BenchmarkProfile from the
+// node.kubernetes.io/instance-type label, for nodes whose ProviderID
+// doesn't use one of the well-known URI schemes.
+func profileFromInstanceType(instanceType string) (BenchmarkProfile, bool) {
+	if instanceType == "" {
+		return "", false
+	}
+	for profile, pattern := range instanceTypePatterns {
+		if pattern.MatchString(instanceType) {
+			return profile, true
+		}
+	}
+	return "", false
+}
+
+// runArgs returns the kube-bench CLI arguments for p, using --benchmark to
+// pin a specific managed-Kubernetes benchmark and --targets otherwise.
+func (p BenchmarkProfile) runArgs() []string {
+	switch p {
+	case BenchmarkEKS:
+		return []string{"run", "--benchmark=eks-1.1.0", "--json"}
+	case BenchmarkGKE:
+		return []string{"run", "--benchmark=gke-1.2.0", "--json"}
+	case BenchmarkAKS:
+		return []string{"run", "--benchmark=aks-1.0", "--json"}
+	case BenchmarkMaster:
+		return []string{"run", "--targets=master", "--json"}
+	case BenchmarkEtcd:
+		return []string{"run", "--targets=etcd", "--json"}
+	case BenchmarkPolicies:
+		return []string{"run", "--targets=policies", "--json"}
+	default:
+		return []string{"run", "--targets=node", "--json"}
+	}
+}
+
+// hostPathNames returns the names of the hostPathSpecs relevant to p. Only
+// the master profile needs every control-plane mount; managed profiles
+// (EKS/GKE/AKS) skip them entirely since the control plane isn't reachable
+// from the node.
+func (p BenchmarkProfile) hostPathNames() []string {
+	switch p {
+	case BenchmarkMaster:
+		return []string{"var-lib-etcd", "var-lib-kubelet", "etc-systemd", "etc-kubernetes", "usr-bin"}
+	case BenchmarkEtcd:
+		return []string{"var-lib-etcd", "etc-kubernetes", "usr-bin"}
+	case BenchmarkPolicies:
+		return []string{"usr-bin"}
+	default: // BenchmarkNode, BenchmarkEKS, BenchmarkGKE, BenchmarkAKS
+		return []string{"var-lib-kubelet", "usr-bin"}
+	}
+}
+
+type hostPathSpec struct {
+	name      string
+	hostPath  string
+	mountPath string
+}
+
+var hostPathSpecs = map[string]hostPathSpec{
+	"var-lib-etcd":    {"var-lib-etcd", "/var/lib/etcd", "/var/lib/etcd"},
+	"var-lib-kubelet": {"var-lib-kubelet", "/var/lib/kubelet", "/var/lib/kubelet"},
+	"etc-systemd":     {"etc-systemd", "/etc/systemd", "/etc/systemd"},
+	"etc-kubernetes":  {"etc-kubernetes", "/etc/kubernetes", "/etc/kubernetes"},
+	"usr-bin":         {"usr-bin", "/usr/bin", "/usr/local/mount-from-host/bin"},
+}
+
+// volumesAndMounts builds the Volumes and VolumeMounts needed to run p,
+// drawn from hostPathSpecs.
+func (p BenchmarkProfile) volumesAndMounts() ([]core.Volume, []core.VolumeMount) {
+	names := p.hostPathNames()
+	volumes := make([]core.Volume, 0, len(names))
+	mounts := make([]core.VolumeMount, 0, len(names))
+	for _, name := range names {
+		spec := hostPathSpecs[name]
+		volumes = append(volumes, core.Volume{
+			Name: spec.name,
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: spec.hostPath,
+				},
+			},
+		})
+		mounts = append(mounts, core.VolumeMount{
+			Name:      spec.name,
+			MountPath: spec.mountPath,
+			ReadOnly:  true,
+		})
+	}
+	return volumes, mounts
+}
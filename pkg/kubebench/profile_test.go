@@ -0,0 +1,78 @@
+package kubebench
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+func TestResolveBenchmarkProfile(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts kube.ScannerOpts
+		node *core.Node
+		want BenchmarkProfile
+	}{
+		{
+			name: "no node yet",
+			node: nil,
+			want: BenchmarkNode,
+		},
+		{
+			name: "override wins regardless of node",
+			opts: kube.ScannerOpts{BenchmarkProfile: "policies"},
+			node: &core.Node{Spec: core.NodeSpec{ProviderID: "aws:///us-east-1a/i-1234567890"}},
+			want: BenchmarkPolicies,
+		},
+		{
+			name: "self-hosted master on a cloud VM is still master",
+			node: &core.Node{
+				ObjectMeta: meta.ObjectMeta{Labels: map[string]string{labelNodeRoleMaster: ""}},
+				Spec:       core.NodeSpec{ProviderID: "aws:///us-east-1a/i-1234567890"},
+			},
+			want: BenchmarkMaster,
+		},
+		{
+			name: "eks worker via provider ID",
+			node: &core.Node{Spec: core.NodeSpec{ProviderID: "aws:///us-east-1a/i-1234567890"}},
+			want: BenchmarkEKS,
+		},
+		{
+			name: "gke worker via provider ID",
+			node: &core.Node{Spec: core.NodeSpec{ProviderID: "gce://my-project/us-central1-a/gke-node-1"}},
+			want: BenchmarkGKE,
+		},
+		{
+			name: "aks worker via provider ID",
+			node: &core.Node{Spec: core.NodeSpec{ProviderID: "azure:///subscriptions/.../aks-node-1"}},
+			want: BenchmarkAKS,
+		},
+		{
+			name: "eks worker via instance-type fallback",
+			node: &core.Node{ObjectMeta: meta.ObjectMeta{Labels: map[string]string{labelInstanceType: "m5.large"}}},
+			want: BenchmarkEKS,
+		},
+		{
+			name: "aks worker via instance-type fallback",
+			node: &core.Node{ObjectMeta: meta.ObjectMeta{Labels: map[string]string{labelInstanceType: "Standard_DS2_v2"}}},
+			want: BenchmarkAKS,
+		},
+		{
+			name: "plain worker node",
+			node: &core.Node{},
+			want: BenchmarkNode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Scanner{opts: tc.opts}
+			if got := s.resolveBenchmarkProfile(tc.node); got != tc.want {
+				t.Errorf("resolveBenchmarkProfile() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
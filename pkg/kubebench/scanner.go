@@ -3,6 +3,8 @@ package kubebench
 import (
 	"context"
 	"fmt"
+	"io"
+	"sync"
 
 	"github.com/aquasecurity/starboard/pkg/scanners"
 
@@ -12,7 +14,6 @@ import (
 
 	"github.com/aquasecurity/starboard/pkg/kube"
 	"github.com/aquasecurity/starboard/pkg/kube/pod"
-	"github.com/aquasecurity/starboard/pkg/runner"
 	"github.com/google/uuid"
 	batch "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
@@ -30,33 +31,186 @@ const (
 type Scanner struct {
 	opts      kube.ScannerOpts
 	clientset kubernetes.Interface
-	pods      *pod.Manager
+	pods      podLogsGetter
 	converter Converter
+	imageRef  string
 	scanners.Base
 }
 
-func NewScanner(opts kube.ScannerOpts, clientset kubernetes.Interface) *Scanner {
+// podLogsGetter is the subset of pod.Manager's API the Scanner needs to
+// fetch a scanned Pod's logs. Kept as an interface, rather than depending on
+// *pod.Manager directly, so tests can inject a fake instead of standing up
+// a real log stream against a fake clientset, which doesn't support one.
+type podLogsGetter interface {
+	GetPodLogs(ctx context.Context, pod *core.Pod, containerName string) (io.ReadCloser, error)
+}
+
+// NewScanner validates opts.KubeBenchImageRef (or the Scanner's built-in
+// default, if unset) and, when opts.ResolveImageDigest is set, resolves it
+// to a digest once so every scan this Scanner runs uses the exact same
+// image.
+func NewScanner(ctx context.Context, opts kube.ScannerOpts, clientset kubernetes.Interface) (*Scanner, error) {
+	imageRef := opts.KubeBenchImageRef
+	if imageRef == "" {
+		imageRef = kubeBenchContainerImage
+	}
+	if err := validateImageRef(imageRef); err != nil {
+		return nil, fmt.Errorf("validating kube-bench image: %w", err)
+	}
+
+	if opts.ResolveImageDigest {
+		digestRef, err := resolveImageDigest(ctx, clientset, kube.NamespaceStarboard, opts.ImagePullSecrets, imageRef)
+		if err != nil {
+			return nil, fmt.Errorf("resolving kube-bench image digest: %w", err)
+		}
+		imageRef = digestRef
+	}
+
 	return &Scanner{
 		opts:      opts,
 		clientset: clientset,
 		pods:      pod.NewPodManager(clientset),
 		converter: DefaultConverter,
+		imageRef:  imageRef,
+	}, nil
+}
+
+// imagePullPolicy returns the configured image pull policy, defaulting to
+// Always so a :latest-style tag is re-checked on every scan.
+func (s *Scanner) imagePullPolicy() core.PullPolicy {
+	if s.opts.ImagePullPolicy != "" {
+		return s.opts.ImagePullPolicy
 	}
+	return core.PullAlways
 }
 
+// imagePullSecrets converts the configured Secret names to the
+// LocalObjectReferences a PodSpec expects.
+func (s *Scanner) imagePullSecrets() []core.LocalObjectReference {
+	if len(s.opts.ImagePullSecrets) == 0 {
+		return nil
+	}
+	secrets := make([]core.LocalObjectReference, len(s.opts.ImagePullSecrets))
+	for i, name := range s.opts.ImagePullSecrets {
+		secrets[i] = core.LocalObjectReference{Name: name}
+	}
+	return secrets
+}
+
+// Scan runs kube-bench once, against a single Node, and returns the
+// resulting CISKubeBenchOutput alongside the Node it ran on. It pins the
+// scan Pod to the first Node returned by the API server, the same way
+// ScanAll pins one Job per Node, so the benchmark profile - and scheduling
+// defaults that follow from it, such as tolerating the master taint - are
+// resolved against a real Node rather than never being resolved at all.
+// Use ScanAll to cover every Node in the cluster.
 func (s *Scanner) Scan(ctx context.Context) (report starboard.CISKubeBenchOutput, node *core.Node, err error) {
-	// 1. Prepare descriptor for the Kubernetes Job which will run kube-bench
-	kubeBenchJob := s.prepareKubeBenchJob()
+	nodeList, err := s.clientset.CoreV1().Nodes().List(ctx, meta.ListOptions{Limit: 1})
+	if err != nil {
+		err = fmt.Errorf("listing nodes: %w", err)
+		return
+	}
+	if len(nodeList.Items) == 0 {
+		err = fmt.Errorf("no nodes found to scan")
+		return
+	}
+	node = &nodeList.Items[0]
 
-	// 2. Run the prepared Job and wait for its completion or failure
-	err = runner.New().Run(ctx, kube.NewRunnableJob(s.clientset, kubeBenchJob))
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	tracker, err := pod.NewPodTracker(ctx, s.clientset, stopCh)
 	if err != nil {
-		err = fmt.Errorf("running kube-bench job: %w", err)
+		err = fmt.Errorf("starting pod tracker: %w", err)
+		return
+	}
+
+	report, err = s.scanNode(ctx, node, tracker)
+	return
+}
+
+// ScanAll runs kube-bench on every Node in the cluster and returns the
+// resulting CISKubeBenchOutput reports keyed by Node name. Unlike Scan,
+// which only scans the first Node returned by the API server, ScanAll pins
+// a Job to each Node via NodeName so that every Node, not just one, is
+// covered.
+//
+// Node scans run with concurrency bounded by s.opts.Concurrency (defaulting
+// to 1), and an error scanning one Node is logged and does not prevent the
+// remaining Nodes from being scanned.
+func (s *Scanner) ScanAll(ctx context.Context) (map[string]starboard.CISKubeBenchOutput, error) {
+	nodeList, err := s.clientset.CoreV1().Nodes().List(ctx, meta.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	tracker, err := pod.NewPodTracker(ctx, s.clientset, stopCh)
+	if err != nil {
+		return nil, fmt.Errorf("starting pod tracker: %w", err)
+	}
+
+	concurrency := s.opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		reports = make(map[string]starboard.CISKubeBenchOutput)
+	)
+
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			report, err := s.scanNode(ctx, node, tracker)
+			if err != nil {
+				klog.Errorf("Scanning node %s: %v", node.Name, err)
+				return
+			}
+
+			mu.Lock()
+			reports[node.Name] = report
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return reports, nil
+}
+
+// scanNode runs kube-bench on node, or lets the scheduler pick a Node when
+// node is nil, by creating a Job and using tracker to wait for its Pod's
+// terminal state, collecting its logs, and converting them to a
+// CISKubeBenchOutput. The Job is deleted before scanNode returns, with
+// background propagation, so its Pod is removed asynchronously. Callers that already have the Node object, such as
+// ScanAll, should pass it directly rather than name alone, so scanNode
+// doesn't have to fetch it again just to resolve a benchmark profile.
+func (s *Scanner) scanNode(ctx context.Context, node *core.Node, tracker *pod.PodTracker) (report starboard.CISKubeBenchOutput, err error) {
+	nodeName := ""
+	if node != nil {
+		nodeName = node.Name
+	}
+	profile := s.resolveBenchmarkProfile(node)
+
+	// 1. Create the Kubernetes Job which will run kube-bench
+	kubeBenchJob := s.prepareKubeBenchJob(nodeName, profile)
+	kubeBenchJob, err = s.clientset.BatchV1().Jobs(kubeBenchJob.Namespace).Create(ctx, kubeBenchJob, meta.CreateOptions{})
+	if err != nil {
+		err = fmt.Errorf("creating kube-bench job: %w", err)
 		return
 	}
 
 	defer func() {
-		// 6. Delete the kube-bench Job
+		// 5. Delete the kube-bench Job
 		klog.V(3).Infof("Deleting job: %s/%s", kubeBenchJob.Namespace, kubeBenchJob.Name)
 		background := meta.DeletePropagationBackground
 		_ = s.clientset.BatchV1().Jobs(kubeBenchJob.Namespace).Delete(ctx, kubeBenchJob.Name, meta.DeleteOptions{
@@ -64,14 +218,14 @@ func (s *Scanner) Scan(ctx context.Context) (report starboard.CISKubeBenchOutput
 		})
 	}()
 
-	// 3. Get the Pod controlled by the kube-bench Job
-	kubeBenchPod, err := s.pods.GetPodByJob(ctx, kubeBenchJob)
+	// 2. Wait for the kube-bench Pod to reach a terminal state
+	kubeBenchPod, err := tracker.WaitForCompletion(ctx, kubeBenchJob.Name)
 	if err != nil {
-		err = fmt.Errorf("getting kube-bench pod: %w", err)
+		err = fmt.Errorf("waiting for kube-bench pod: %w", err)
 		return
 	}
 
-	// 4. Get kube-bench JSON output from the kube-bench Pod
+	// 3. Get kube-bench JSON output from the kube-bench Pod
 	klog.V(3).Infof("Getting logs for %s container in job: %s/%s", kubeBenchContainerName,
 		kubeBenchJob.Namespace, kubeBenchJob.Name)
 	logsReader, err := s.pods.GetPodLogs(ctx, kubeBenchPod, kubeBenchContainerName)
@@ -83,18 +237,19 @@ func (s *Scanner) Scan(ctx context.Context) (report starboard.CISKubeBenchOutput
 		_ = logsReader.Close()
 	}()
 
-	// 5. Parse the CISBenchmarkReport from the logs Reader
+	// 4. Parse the CISBenchmarkReport from the logs Reader
 	report, err = s.converter.Convert(logsReader)
 	if err != nil {
 		err = fmt.Errorf("parsing CIS benchmark report: %w", err)
 		return
 	}
 
-	node, err = s.clientset.CoreV1().Nodes().Get(ctx, kubeBenchPod.Spec.NodeName, meta.GetOptions{})
 	return
 }
 
-func (s *Scanner) prepareKubeBenchJob() *batch.Job {
+func (s *Scanner) prepareKubeBenchJob(nodeName string, profile BenchmarkProfile) *batch.Job {
+	volumes, volumeMounts := profile.volumesAndMounts()
+
 	return &batch.Job{
 		ObjectMeta: meta.ObjectMeta{
 			Name:      uuid.New().String(),
@@ -114,85 +269,25 @@ func (s *Scanner) prepareKubeBenchJob() *batch.Job {
 					},
 				},
 				Spec: core.PodSpec{
-					RestartPolicy: core.RestartPolicyNever,
-					HostPID:       true,
-					Volumes: []core.Volume{
-						{
-							Name: "var-lib-etcd",
-							VolumeSource: core.VolumeSource{
-								HostPath: &core.HostPathVolumeSource{
-									Path: "/var/lib/etcd",
-								},
-							},
-						},
-						{
-							Name: "var-lib-kubelet",
-							VolumeSource: core.VolumeSource{
-								HostPath: &core.HostPathVolumeSource{
-									Path: "/var/lib/kubelet",
-								},
-							},
-						},
-						{
-							Name: "etc-systemd",
-							VolumeSource: core.VolumeSource{
-								HostPath: &core.HostPathVolumeSource{
-									Path: "/etc/systemd",
-								},
-							},
-						},
-						{
-							Name: "etc-kubernetes",
-							VolumeSource: core.VolumeSource{
-								HostPath: &core.HostPathVolumeSource{
-									Path: "/etc/kubernetes",
-								},
-							},
-						},
-						{
-							Name: "usr-bin",
-							VolumeSource: core.VolumeSource{
-								HostPath: &core.HostPathVolumeSource{
-									Path: "/usr/bin",
-								},
-							},
-						},
-					},
+					RestartPolicy:      core.RestartPolicyNever,
+					HostPID:            true,
+					NodeName:           nodeName,
+					NodeSelector:       s.nodeSelector(profile),
+					Tolerations:        s.tolerations(profile),
+					Affinity:           s.opts.Affinity,
+					ServiceAccountName: s.opts.ServiceAccountName,
+					PriorityClassName:  s.priorityClassName(profile),
+					ImagePullSecrets:   s.imagePullSecrets(),
+					Volumes:            volumes,
 					Containers: []core.Container{
 						{
 							Name:                     kubeBenchContainerName,
-							Image:                    kubeBenchContainerImage,
-							ImagePullPolicy:          core.PullAlways,
+							Image:                    s.imageRef,
+							ImagePullPolicy:          s.imagePullPolicy(),
 							TerminationMessagePolicy: core.TerminationMessageFallbackToLogsOnError,
 							Command:                  []string{"kube-bench"},
-							Args:                     []string{"--json"},
-							VolumeMounts: []core.VolumeMount{
-								{
-									Name:      "var-lib-etcd",
-									MountPath: "/var/lib/etcd",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "var-lib-kubelet",
-									MountPath: "/var/lib/kubelet",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "etc-systemd",
-									MountPath: "/etc/systemd",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "etc-kubernetes",
-									MountPath: "/etc/kubernetes",
-									ReadOnly:  true,
-								},
-								{
-									Name:      "usr-bin",
-									MountPath: "/usr/local/mount-from-host/bin",
-									ReadOnly:  true,
-								},
-							},
+							Args:                     profile.runArgs(),
+							VolumeMounts:             volumeMounts,
 						},
 					},
 				},
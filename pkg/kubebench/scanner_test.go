@@ -0,0 +1,104 @@
+package kubebench
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	starboard "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+// stubLogsGetter stands in for pod.Manager in tests: the fake clientset
+// doesn't support streaming real Pod logs, so scanNode's log-fetching step
+// is faked out rather than exercised.
+type stubLogsGetter struct{}
+
+func (stubLogsGetter) GetPodLogs(_ context.Context, _ *core.Pod, _ string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("{}")), nil
+}
+
+// stubConverter stands in for the real kube-bench JSON Converter; ScanAll's
+// aggregation logic doesn't care what a report contains, only that one was
+// produced.
+type stubConverter struct{}
+
+func (stubConverter) Convert(_ io.Reader) (starboard.CISKubeBenchOutput, error) {
+	return starboard.CISKubeBenchOutput{}, nil
+}
+
+// TestScanAll_AggregatesAcrossNodes drives ScanAll against a fake clientset.
+// A reactor on Job creation stands in for the kubelet/Job controller,
+// creating each Job's Pod directly so PodTracker can resolve it: node-1's
+// Pod succeeds, node-2's gets stuck in ImagePullBackOff. ScanAll is expected
+// to still return node-1's report rather than have node-2's failure abort
+// the whole scan.
+func TestScanAll_AggregatesAcrossNodes(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "node-1"}},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "node-2"}},
+	)
+
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batch.Job)
+		nodeName := job.Spec.Template.Spec.NodeName
+
+		scanPod := &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      job.Name + "-pod",
+				Namespace: job.Namespace,
+				Labels: map[string]string{
+					"app":      "kube-bench",
+					"job-name": job.Name,
+				},
+			},
+			Spec: core.PodSpec{NodeName: nodeName},
+		}
+
+		switch nodeName {
+		case "node-1":
+			scanPod.Status.Phase = core.PodSucceeded
+		case "node-2":
+			scanPod.Status.ContainerStatuses = []core.ContainerStatus{
+				{State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			}
+		}
+
+		// Create the Pod asynchronously so this reactor returns promptly and
+		// the Job create it's intercepting can still proceed; PodTracker's
+		// informer picks the Pod up once it lands.
+		go func() {
+			_, _ = clientset.CoreV1().Pods(job.Namespace).Create(context.Background(), scanPod, meta.CreateOptions{})
+		}()
+
+		return false, nil, nil
+	})
+
+	s := &Scanner{
+		opts:      kube.ScannerOpts{Concurrency: 2},
+		clientset: clientset,
+		pods:      stubLogsGetter{},
+		converter: stubConverter{},
+		imageRef:  "aquasec/kube-bench:latest",
+	}
+
+	reports, err := s.ScanAll(context.Background())
+	if err != nil {
+		t.Fatalf("ScanAll() error = %v", err)
+	}
+
+	if _, ok := reports["node-1"]; !ok {
+		t.Errorf("ScanAll() reports = %v, want a report for node-1", reports)
+	}
+	if _, ok := reports["node-2"]; ok {
+		t.Errorf("ScanAll() reports = %v, want no report for node-2 (stuck image pull)", reports)
+	}
+}
@@ -0,0 +1,56 @@
+package kubebench
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// priorityClassSystemClusterCritical keeps master scans from being evicted
+// under pressure on the very nodes the CIS master benchmark targets.
+const priorityClassSystemClusterCritical = "system-cluster-critical"
+
+// nodeSelector returns the NodeSelector for a scan Pod targeting profile,
+// preferring the caller's override over the profile's own default. The
+// master profile selects node-role.kubernetes.io/master so the Pod only
+// ever lands on a control-plane Node even when NodeName is left for the
+// scheduler to pick.
+func (s *Scanner) nodeSelector(profile BenchmarkProfile) map[string]string {
+	if len(s.opts.NodeSelector) > 0 {
+		return s.opts.NodeSelector
+	}
+	if profile == BenchmarkMaster {
+		return map[string]string{labelNodeRoleMaster: ""}
+	}
+	return nil
+}
+
+// tolerations returns the Tolerations for a scan Pod targeting profile,
+// preferring the caller's override over the profile's own default. The
+// master profile tolerates the master taint, since that's the exact taint
+// hardened clusters use to keep workloads off the control plane.
+func (s *Scanner) tolerations(profile BenchmarkProfile) []core.Toleration {
+	if len(s.opts.Tolerations) > 0 {
+		return s.opts.Tolerations
+	}
+	if profile == BenchmarkMaster {
+		return []core.Toleration{
+			{
+				Key:    labelNodeRoleMaster,
+				Effect: core.TaintEffectNoSchedule,
+			},
+		}
+	}
+	return nil
+}
+
+// priorityClassName returns the PriorityClassName for a scan Pod targeting
+// profile, preferring the caller's override over the profile's own
+// default.
+func (s *Scanner) priorityClassName(profile BenchmarkProfile) string {
+	if s.opts.PriorityClassName != "" {
+		return s.opts.PriorityClassName
+	}
+	if profile == BenchmarkMaster {
+		return priorityClassSystemClusterCritical
+	}
+	return ""
+}
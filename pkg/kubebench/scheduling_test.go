@@ -0,0 +1,69 @@
+package kubebench
+
+import (
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+func TestSchedulingDefaults(t *testing.T) {
+	masterTolerations := []core.Toleration{
+		{Key: labelNodeRoleMaster, Effect: core.TaintEffectNoSchedule},
+	}
+
+	testCases := []struct {
+		name                  string
+		opts                  kube.ScannerOpts
+		profile               BenchmarkProfile
+		wantNodeSelector      map[string]string
+		wantTolerations       []core.Toleration
+		wantPriorityClassName string
+	}{
+		{
+			name:                  "master profile gets master defaults",
+			profile:               BenchmarkMaster,
+			wantNodeSelector:      map[string]string{labelNodeRoleMaster: ""},
+			wantTolerations:       masterTolerations,
+			wantPriorityClassName: priorityClassSystemClusterCritical,
+		},
+		{
+			name:    "node profile gets no defaults",
+			profile: BenchmarkNode,
+		},
+		{
+			name:    "eks profile gets no master defaults",
+			profile: BenchmarkEKS,
+		},
+		{
+			name:    "caller overrides win over master defaults",
+			profile: BenchmarkMaster,
+			opts: kube.ScannerOpts{
+				NodeSelector:      map[string]string{"disktype": "ssd"},
+				Tolerations:       []core.Toleration{{Key: "dedicated", Effect: core.TaintEffectNoExecute}},
+				PriorityClassName: "custom-priority",
+			},
+			wantNodeSelector:      map[string]string{"disktype": "ssd"},
+			wantTolerations:       []core.Toleration{{Key: "dedicated", Effect: core.TaintEffectNoExecute}},
+			wantPriorityClassName: "custom-priority",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Scanner{opts: tc.opts}
+
+			if got := s.nodeSelector(tc.profile); !reflect.DeepEqual(got, tc.wantNodeSelector) {
+				t.Errorf("nodeSelector() = %v, want %v", got, tc.wantNodeSelector)
+			}
+			if got := s.tolerations(tc.profile); !reflect.DeepEqual(got, tc.wantTolerations) {
+				t.Errorf("tolerations() = %v, want %v", got, tc.wantTolerations)
+			}
+			if got := s.priorityClassName(tc.profile); got != tc.wantPriorityClassName {
+				t.Errorf("priorityClassName() = %q, want %q", got, tc.wantPriorityClassName)
+			}
+		})
+	}
+}